@@ -0,0 +1,154 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const filterArray = "$filter[]"
+
+// RegisterArrayField opts a field into the repeated-key array shorthand,
+// e.g. `?Genre=Thriller&Genre=Horror`, which compiles to an `IN` clause
+// over the field's registered values.
+func (f *DataFilter) RegisterArrayField(name string) {
+	if f.arrayFields == nil {
+		f.arrayFields = make(map[string]bool)
+	}
+
+	f.arrayFields[name] = true
+}
+
+// parseArrayFilters combines the repeated `$filter[]=` query option (AND-joined)
+// with any registered array fields found as repeated query-string keys
+// (compiled to `IN` clauses), returning a single Expr to AND with $filter.
+func parseArrayFilters(url string, fieldMap fieldData, arrayFields map[string]bool) (Expr, error) {
+	var exprs []Expr
+
+	for _, raw := range parseQueryOptions(url, filterArray) {
+		p, err := NewParser(raw, fieldMap)
+		if err != nil {
+			return nil, err
+		}
+
+		expr, err := p.Parse()
+		if err != nil {
+			return nil, err
+		}
+
+		exprs = append(exprs, expr)
+	}
+
+	names := make([]string, 0, len(arrayFields))
+	for name := range arrayFields {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		field, ok := fieldMap[name]
+		if !ok {
+			continue
+		}
+
+		vals := parseQueryOptions(url, name)
+		if len(vals) == 0 {
+			continue
+		}
+
+		values := make([]string, len(vals))
+		for i, v := range vals {
+			values[i] = asLiteral(v)
+		}
+
+		exprs = append(exprs, &InExpr{Field: field, Values: values})
+	}
+
+	return andAll(exprs), nil
+}
+
+// combineFilters ANDs two possibly-nil filter Exprs together, returning
+// whichever side is non-nil if the other is absent.
+func combineFilters(a, b Expr) Expr {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	default:
+		return &AndExpr{Left: a, Right: b}
+	}
+}
+
+// andAll folds exprs into a single left-leaning AndExpr chain, or nil if empty.
+func andAll(exprs []Expr) Expr {
+	if len(exprs) == 0 {
+		return nil
+	}
+
+	result := exprs[0]
+	for _, e := range exprs[1:] {
+		result = &AndExpr{Left: result, Right: e}
+	}
+
+	return result
+}
+
+// asLiteral renders a raw, unquoted query-string value as a lexer-style
+// literal: bare if numeric, single-quoted otherwise.
+func asLiteral(raw string) string {
+	if _, err := strconv.ParseFloat(raw, 64); err == nil {
+		return raw
+	}
+
+	return "'" + raw + "'"
+}
+
+// parseQueryOptions returns every value bound to the repeated query-string
+// key opt, in appearance order, unlike parseQueryOption which returns only
+// the first match.
+func parseQueryOptions(query, opt string) []string {
+	var vals []string
+
+	for _, p := range splitQueryPairs(query) {
+		if p.key == opt {
+			vals = append(vals, p.value)
+		}
+	}
+
+	return vals
+}
+
+// queryPair is one key=value pair of a raw URL query string.
+type queryPair struct {
+	key   string
+	value string
+}
+
+// splitQueryPairs splits the query portion of a raw URL into its key=value
+// pairs, with `&` as the sole pair separator. A key is matched exactly
+// against the pair it belongs to, never against a substring of a sibling
+// pair's key or value — unlike a bare `opt=...` regex scan, which happily
+// matches `opt=` wherever it occurs, including inside an unrelated field
+// name (e.g. `Name` inside `LastName=`) or inside another option's own
+// value (e.g. a `$top=` embedded in a `$expand=...($top=5;...)` relation).
+func splitQueryPairs(rawURL string) []queryPair {
+	query := rawURL
+	if i := strings.IndexByte(query, '?'); i != -1 {
+		query = query[i+1:]
+	}
+
+	var pairs []queryPair
+
+	for _, raw := range strings.Split(query, "&") {
+		if raw == "" {
+			continue
+		}
+
+		key, value, _ := strings.Cut(raw, "=")
+		pairs = append(pairs, queryPair{key: key, value: value})
+	}
+
+	return pairs
+}
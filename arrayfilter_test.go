@@ -0,0 +1,112 @@
+package main
+
+import "testing"
+
+func TestParseArrayFilters_FilterArrayOptionIsANDJoined(t *testing.T) {
+	expr, err := parseArrayFilters("/books?$filter[]=Rate gt 100&$filter[]=Genre eq 'Thriller'", testFields, nil)
+	if err != nil {
+		t.Fatalf("parseArrayFilters: %v", err)
+	}
+
+	and, ok := expr.(*AndExpr)
+	if !ok {
+		t.Fatalf("expected *AndExpr, got %T", expr)
+	}
+
+	if _, ok := and.Left.(*Comparison); !ok {
+		t.Fatalf("expected AndExpr.Left to be *Comparison, got %T", and.Left)
+	}
+
+	if _, ok := and.Right.(*Comparison); !ok {
+		t.Fatalf("expected AndExpr.Right to be *Comparison, got %T", and.Right)
+	}
+}
+
+func TestParseArrayFilters_RegisteredFieldCompilesToIn(t *testing.T) {
+	arrayFields := map[string]bool{"Genre": true}
+
+	expr, err := parseArrayFilters("/books?Genre=Thriller&Genre=Horror", testFields, arrayFields)
+	if err != nil {
+		t.Fatalf("parseArrayFilters: %v", err)
+	}
+
+	in, ok := expr.(*InExpr)
+	if !ok {
+		t.Fatalf("expected *InExpr, got %T", expr)
+	}
+
+	if in.Field != "genre" {
+		t.Fatalf("Field = %q, want %q", in.Field, "genre")
+	}
+
+	want := []string{"'Thriller'", "'Horror'"}
+	if len(in.Values) != len(want) {
+		t.Fatalf("Values = %v, want %v", in.Values, want)
+	}
+
+	for i, v := range want {
+		if in.Values[i] != v {
+			t.Fatalf("Values[%d] = %q, want %q", i, in.Values[i], v)
+		}
+	}
+}
+
+func TestParseArrayFilters_UnregisteredKeyIsIgnored(t *testing.T) {
+	expr, err := parseArrayFilters("/books?Genre=Thriller", testFields, nil)
+	if err != nil {
+		t.Fatalf("parseArrayFilters: %v", err)
+	}
+
+	if expr != nil {
+		t.Fatalf("expected nil expr for an unregistered array field, got %+v", expr)
+	}
+}
+
+func TestCombineFilters(t *testing.T) {
+	a := &Comparison{Field: "rate", Op: ">", Value: "100"}
+	b := &Comparison{Field: "genre", Op: "=", Value: "'Thriller'"}
+
+	if got := combineFilters(nil, nil); got != nil {
+		t.Fatalf("combineFilters(nil, nil) = %+v, want nil", got)
+	}
+
+	if got := combineFilters(a, nil); got != Expr(a) {
+		t.Fatalf("combineFilters(a, nil) = %+v, want a", got)
+	}
+
+	if got := combineFilters(nil, b); got != Expr(b) {
+		t.Fatalf("combineFilters(nil, b) = %+v, want b", got)
+	}
+
+	and, ok := combineFilters(a, b).(*AndExpr)
+	if !ok {
+		t.Fatalf("combineFilters(a, b) = %T, want *AndExpr", combineFilters(a, b))
+	}
+
+	if and.Left != Expr(a) || and.Right != Expr(b) {
+		t.Fatalf("combineFilters(a, b) = %+v, want AndExpr{a, b}", and)
+	}
+}
+
+func TestParseQueryOptions_KeyMatchIsExact(t *testing.T) {
+	// A "Name"-registered lookup must not match the "LastName" pair's value,
+	// and a "$filter[]" lookup must not be confused by regex metacharacters
+	// in its own key.
+	got := parseQueryOptions("/people?LastName=Smith", "Name")
+	if len(got) != 0 {
+		t.Fatalf("parseQueryOptions(Name) = %v, want none (must not match inside LastName)", got)
+	}
+
+	got = parseQueryOptions("/books?$filter[]=Rate gt 100&$filter[]=Genre eq 'X'", "$filter[]")
+	want := []string{"Rate gt 100", "Genre eq 'X'"}
+
+	if len(got) != len(want) {
+		t.Fatalf("parseQueryOptions($filter[]) = %v, want %v", got, want)
+	}
+
+	for i, v := range want {
+		if got[i] != v {
+			t.Fatalf("parseQueryOptions($filter[])[%d] = %q, want %q", i, got[i], v)
+		}
+	}
+}
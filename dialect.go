@@ -0,0 +1,113 @@
+package main
+
+import "fmt"
+
+// Dialect abstracts the SQL placeholder syntax and pagination clause of a
+// specific database engine, so SQLBuilder can stay database-agnostic.
+type Dialect interface {
+	// Name identifies the dialect, e.g. "postgres", used to key per-dialect
+	// FuncRegistry emitters.
+	Name() string
+
+	// Placeholder returns the bind-parameter marker for the n-th argument
+	// (1-based position), e.g. "$1" on Postgres or "?" on MySQL/SQLite.
+	Placeholder(n int) string
+
+	// Paginate renders the LIMIT/OFFSET (or dialect equivalent) clause for
+	// the given $top/$skip values. Either argument may be nil.
+	Paginate(top, skip *int) string
+
+	// NullsClause renders the trailing NULLS FIRST/LAST modifier for an
+	// ORDER BY key, or "" if nullsFirst is nil or the dialect has no native
+	// nulls-ordering syntax.
+	NullsClause(nullsFirst *bool) string
+}
+
+// nullsClause renders " NULLS FIRST"/" NULLS LAST" for dialects (Postgres,
+// SQLite) that support the standard nulls-ordering syntax natively.
+func nullsClause(nullsFirst *bool) string {
+	if nullsFirst == nil {
+		return ""
+	}
+
+	if *nullsFirst {
+		return " NULLS FIRST"
+	}
+
+	return " NULLS LAST"
+}
+
+// PostgresDialect emits $1, $2, ... placeholders and LIMIT/OFFSET pagination.
+type PostgresDialect struct{}
+
+func (PostgresDialect) Name() string { return "postgres" }
+
+func (PostgresDialect) Placeholder(n int) string { return fmt.Sprintf("$%d", n) }
+
+func (PostgresDialect) Paginate(top, skip *int) string { return limitOffset(top, skip) }
+
+func (PostgresDialect) NullsClause(nullsFirst *bool) string { return nullsClause(nullsFirst) }
+
+// MySQLDialect emits `?` placeholders and LIMIT/OFFSET pagination.
+type MySQLDialect struct{}
+
+func (MySQLDialect) Name() string { return "mysql" }
+
+func (MySQLDialect) Placeholder(int) string { return "?" }
+
+func (MySQLDialect) Paginate(top, skip *int) string { return limitOffset(top, skip) }
+
+// NullsClause returns "": MySQL has no native NULLS FIRST/LAST syntax.
+func (MySQLDialect) NullsClause(*bool) string { return "" }
+
+// SQLiteDialect emits `?` placeholders and LIMIT/OFFSET pagination.
+type SQLiteDialect struct{}
+
+func (SQLiteDialect) Name() string { return "sqlite" }
+
+func (SQLiteDialect) Placeholder(int) string { return "?" }
+
+func (SQLiteDialect) Paginate(top, skip *int) string { return limitOffset(top, skip) }
+
+func (SQLiteDialect) NullsClause(nullsFirst *bool) string { return nullsClause(nullsFirst) }
+
+// SQLServerDialect emits @p1, @p2, ... placeholders and
+// OFFSET ... ROWS FETCH NEXT ... ROWS ONLY pagination.
+type SQLServerDialect struct{}
+
+func (SQLServerDialect) Name() string { return "sqlserver" }
+
+func (SQLServerDialect) Placeholder(n int) string { return fmt.Sprintf("@p%d", n) }
+
+func (SQLServerDialect) Paginate(top, skip *int) string {
+	var n int
+	if skip != nil {
+		n = *skip
+	}
+
+	clause := fmt.Sprintf("\nOFFSET %d ROWS", n)
+	if top != nil {
+		clause += fmt.Sprintf(" FETCH NEXT %d ROWS ONLY", *top)
+	}
+
+	return clause
+}
+
+// NullsClause returns "": SQL Server has no native NULLS FIRST/LAST syntax.
+func (SQLServerDialect) NullsClause(*bool) string { return "" }
+
+// limitOffset renders the LIMIT/OFFSET pagination shared by Postgres,
+// MySQL and SQLite.
+func limitOffset(top, skip *int) string {
+	var clause string
+
+	if skip != nil {
+		clause += fmt.Sprintf("\nOFFSET %d", *skip)
+	}
+
+	if top != nil {
+		clause += fmt.Sprintf("\nLIMIT %d", *top)
+	}
+
+	return clause
+}
@@ -0,0 +1,71 @@
+package main
+
+import "testing"
+
+func TestDialect_Placeholder(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		n       int
+		want    string
+	}{
+		{PostgresDialect{}, 1, "$1"},
+		{PostgresDialect{}, 2, "$2"},
+		{MySQLDialect{}, 1, "?"},
+		{SQLiteDialect{}, 3, "?"},
+		{SQLServerDialect{}, 1, "@p1"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.dialect.Placeholder(tt.n); got != tt.want {
+			t.Errorf("%s.Placeholder(%d) = %q, want %q", tt.dialect.Name(), tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestDialect_Paginate(t *testing.T) {
+	top, skip := 10, 20
+
+	tests := []struct {
+		name    string
+		dialect Dialect
+		top     *int
+		skip    *int
+		want    string
+	}{
+		{"postgres limit/offset", PostgresDialect{}, &top, &skip, "\nOFFSET 20\nLIMIT 10"},
+		{"mysql limit/offset", MySQLDialect{}, &top, &skip, "\nOFFSET 20\nLIMIT 10"},
+		{"sqlserver offset/fetch", SQLServerDialect{}, &top, &skip, "\nOFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY"},
+		{"sqlserver offset only", SQLServerDialect{}, nil, &skip, "\nOFFSET 20 ROWS"},
+		{"postgres no pagination", PostgresDialect{}, nil, nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.dialect.Paginate(tt.top, tt.skip); got != tt.want {
+				t.Errorf("Paginate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDialect_NullsClause(t *testing.T) {
+	yes, no := true, false
+
+	pg := PostgresDialect{}
+
+	if got := pg.NullsClause(&yes); got != " NULLS FIRST" {
+		t.Errorf("Postgres NullsClause(true) = %q", got)
+	}
+
+	if got := pg.NullsClause(&no); got != " NULLS LAST" {
+		t.Errorf("Postgres NullsClause(false) = %q", got)
+	}
+
+	if got := pg.NullsClause(nil); got != "" {
+		t.Errorf("Postgres NullsClause(nil) = %q, want empty", got)
+	}
+
+	if got := (MySQLDialect{}).NullsClause(&yes); got != "" {
+		t.Errorf("MySQL NullsClause(true) = %q, want empty (no native syntax)", got)
+	}
+}
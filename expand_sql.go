@@ -0,0 +1,79 @@
+package main
+
+import "fmt"
+
+// BuildExpand renders one SQL fragment per ExpandItem: a correlated
+// `json_agg` subquery on Postgres, or a LEFT JOIN on every other dialect,
+// restricted by the item's own nested filter when one was given. Fragments
+// are returned in order. Every literal bound along the way is appended to
+// args rather than returned separately, so its placeholder number reflects
+// args' length at the point of binding — pass the same args already used
+// by Build (e.g. `sql, args, _ := b.Build(f); clauses, _ := b.BuildExpand(f.Expand, &args)`)
+// so numbering stays global across the whole assembled query instead of
+// restarting at 1 per call or per item.
+func (b *SQLBuilder) BuildExpand(expand []ExpandItem, args *[]any) ([]string, error) {
+	clauses := make([]string, 0, len(expand))
+
+	for _, item := range expand {
+		clause, err := b.buildExpandItem(item, args)
+		if err != nil {
+			return nil, err
+		}
+
+		clauses = append(clauses, clause)
+	}
+
+	return clauses, nil
+}
+
+func (b *SQLBuilder) buildExpandItem(item ExpandItem, args *[]any) (string, error) {
+	if _, ok := b.Dialect.(PostgresDialect); ok {
+		return b.buildExpandJSONAgg(item, args)
+	}
+
+	return b.buildExpandJoin(item, args)
+}
+
+// buildExpandJoin renders `LEFT JOIN table ON local = foreign`, ANDing in the
+// item's own nested filter when present.
+func (b *SQLBuilder) buildExpandJoin(item ExpandItem, args *[]any) (string, error) {
+	clause := fmt.Sprintf("LEFT JOIN %s ON %s = %s", item.JoinTable, item.LocalKey, item.ForeignKey)
+
+	if item.Filter == nil || item.Filter.Filter == nil {
+		return clause, nil
+	}
+
+	cond, err := b.buildExpr(item.Filter.Filter, args)
+	if err != nil {
+		return "", err
+	}
+
+	return clause + " AND " + cond, nil
+}
+
+// buildExpandJSONAgg renders a correlated `json_agg` subquery aliased to the
+// relation name, Postgres' native way to expand a to-many relation without a
+// row-multiplying JOIN. The item's own filter/sort/pagination apply inside
+// the subquery.
+func (b *SQLBuilder) buildExpandJSONAgg(item ExpandItem, args *[]any) (string, error) {
+	inner := fmt.Sprintf("SELECT * FROM %s WHERE %s = %s", item.JoinTable, item.LocalKey, item.ForeignKey)
+
+	if item.Filter != nil {
+		if item.Filter.Filter != nil {
+			cond, err := b.buildExpr(item.Filter.Filter, args)
+			if err != nil {
+				return "", err
+			}
+
+			inner += " AND " + cond
+		}
+
+		if len(item.Filter.OrderBy) > 0 {
+			inner += "\nORDER BY " + b.buildOrderBy(item.Filter.OrderBy)
+		}
+
+		inner += b.Dialect.Paginate(item.Filter.Top, item.Filter.Skip)
+	}
+
+	return fmt.Sprintf("(SELECT json_agg(t) FROM (%s) t) AS %s", inner, item.Relation), nil
+}
@@ -0,0 +1,178 @@
+package main
+
+import "testing"
+
+var testExpandData = expandData{
+	"Reviews": {Table: "reviews", LocalKey: "id", ForeignKey: "book_id"},
+}
+
+func TestParseExpand_NestedOptions(t *testing.T) {
+	items, err := parseExpand("/books?$expand=Reviews($top=5;$filter=Rating gt 3)", fieldData{"Rating": "rating"}, testExpandData)
+	if err != nil {
+		t.Fatalf("parseExpand: %v", err)
+	}
+
+	if len(items) != 1 {
+		t.Fatalf("items = %+v, want 1", items)
+	}
+
+	item := items[0]
+	if item.Relation != "Reviews" || item.JoinTable != "reviews" || item.LocalKey != "id" || item.ForeignKey != "book_id" {
+		t.Fatalf("unexpected item: %+v", item)
+	}
+
+	if item.Filter == nil || item.Filter.Top == nil || *item.Filter.Top != 5 {
+		t.Fatalf("expected nested $top=5, got %+v", item.Filter)
+	}
+
+	if item.Filter.Filter == nil {
+		t.Fatalf("expected nested $filter to be parsed")
+	}
+}
+
+func TestParseExpand_UnknownRelationRejected(t *testing.T) {
+	if _, err := parseExpand("/books?$expand=Bogus", fieldData{}, testExpandData); err == nil {
+		t.Fatal("expected error for unknown expand relation")
+	}
+}
+
+func TestBuildExpand_NonPostgresEmitsLeftJoin(t *testing.T) {
+	items := []ExpandItem{{Relation: "Reviews", JoinTable: "reviews", LocalKey: "id", ForeignKey: "book_id"}}
+
+	var args []any
+
+	clauses, err := NewSQLBuilder(MySQLDialect{}).BuildExpand(items, &args)
+	if err != nil {
+		t.Fatalf("BuildExpand: %v", err)
+	}
+
+	want := "LEFT JOIN reviews ON id = book_id"
+	if len(clauses) != 1 || clauses[0] != want {
+		t.Fatalf("clauses = %v, want [%q]", clauses, want)
+	}
+
+	if len(args) != 0 {
+		t.Fatalf("args = %v, want none", args)
+	}
+}
+
+func TestBuildExpand_PostgresEmitsJSONAggSubquery(t *testing.T) {
+	items := []ExpandItem{{Relation: "Reviews", JoinTable: "reviews", LocalKey: "id", ForeignKey: "book_id"}}
+
+	var args []any
+
+	clauses, err := NewSQLBuilder(PostgresDialect{}).BuildExpand(items, &args)
+	if err != nil {
+		t.Fatalf("BuildExpand: %v", err)
+	}
+
+	want := "(SELECT json_agg(t) FROM (SELECT * FROM reviews WHERE id = book_id) t) AS Reviews"
+	if len(clauses) != 1 || clauses[0] != want {
+		t.Fatalf("clauses = %v, want [%q]", clauses, want)
+	}
+}
+
+func TestBuildExpand_JoinANDsNestedFilter(t *testing.T) {
+	items := []ExpandItem{{
+		Relation:   "Reviews",
+		JoinTable:  "reviews",
+		LocalKey:   "id",
+		ForeignKey: "book_id",
+		Filter:     &DataFilter{Filter: &Comparison{Field: "rating", Op: ">", Value: "3"}},
+	}}
+
+	var args []any
+
+	clauses, err := NewSQLBuilder(MySQLDialect{}).BuildExpand(items, &args)
+	if err != nil {
+		t.Fatalf("BuildExpand: %v", err)
+	}
+
+	want := "LEFT JOIN reviews ON id = book_id AND rating > ?"
+	if len(clauses) != 1 || clauses[0] != want {
+		t.Fatalf("clauses = %v, want [%q]", clauses, want)
+	}
+
+	if len(args) != 1 || args[0] != int64(3) {
+		t.Fatalf("args = %v, want [3]", args)
+	}
+}
+
+// TestBuildExpand_GlobalPlaceholderNumberingAcrossItems guards against
+// BuildExpand restarting each item's placeholder numbering at 1: on Postgres,
+// two expand items with their own nested filters must number $1 and $2, not
+// both emit $1, when their literals are appended to one shared args slice —
+// the composition pattern SQLBuilder.Build's doc comment prescribes.
+func TestBuildExpand_GlobalPlaceholderNumberingAcrossItems(t *testing.T) {
+	items := []ExpandItem{
+		{
+			Relation:   "Reviews",
+			JoinTable:  "reviews",
+			LocalKey:   "id",
+			ForeignKey: "book_id",
+			Filter:     &DataFilter{Filter: &Comparison{Field: "rating", Op: ">", Value: "3"}},
+		},
+		{
+			Relation:   "Comments",
+			JoinTable:  "comments",
+			LocalKey:   "id",
+			ForeignKey: "book_id",
+			Filter:     &DataFilter{Filter: &Comparison{Field: "flagged", Op: "=", Value: "0"}},
+		},
+	}
+
+	var args []any
+
+	clauses, err := NewSQLBuilder(PostgresDialect{}).BuildExpand(items, &args)
+	if err != nil {
+		t.Fatalf("BuildExpand: %v", err)
+	}
+
+	wantReviews := "(SELECT json_agg(t) FROM (SELECT * FROM reviews WHERE id = book_id AND rating > $1) t) AS Reviews"
+	wantComments := "(SELECT json_agg(t) FROM (SELECT * FROM comments WHERE id = book_id AND flagged = $2) t) AS Comments"
+
+	if len(clauses) != 2 || clauses[0] != wantReviews || clauses[1] != wantComments {
+		t.Fatalf("clauses = %v, want [%q %q]", clauses, wantReviews, wantComments)
+	}
+
+	if len(args) != 2 || args[0] != int64(3) || args[1] != int64(0) {
+		t.Fatalf("args = %v, want [3 0]", args)
+	}
+}
+
+// TestBuildExpand_ContinuesNumberingAfterOuterBuild guards the documented
+// composition pattern directly: an outer Build() WHERE clause binds $1, and
+// a subsequent BuildExpand call sharing the same args slice must continue at
+// $2 rather than colliding back down to $1.
+func TestBuildExpand_ContinuesNumberingAfterOuterBuild(t *testing.T) {
+	b := NewSQLBuilder(PostgresDialect{})
+
+	outer := &DataFilter{Filter: &Comparison{Field: "title", Op: "=", Value: "'Dune'"}}
+
+	_, args, err := b.Build(outer)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	items := []ExpandItem{{
+		Relation:   "Reviews",
+		JoinTable:  "reviews",
+		LocalKey:   "id",
+		ForeignKey: "book_id",
+		Filter:     &DataFilter{Filter: &Comparison{Field: "rating", Op: ">", Value: "3"}},
+	}}
+
+	clauses, err := b.BuildExpand(items, &args)
+	if err != nil {
+		t.Fatalf("BuildExpand: %v", err)
+	}
+
+	want := "(SELECT json_agg(t) FROM (SELECT * FROM reviews WHERE id = book_id AND rating > $2) t) AS Reviews"
+	if len(clauses) != 1 || clauses[0] != want {
+		t.Fatalf("clauses = %v, want [%q]", clauses, want)
+	}
+
+	if len(args) != 2 || args[0] != "Dune" || args[1] != int64(3) {
+		t.Fatalf("args = %v, want [Dune 3]", args)
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FuncArgKind describes whether a function argument (beyond the leading
+// field argument every registered function takes) is a field reference,
+// resolved against the caller's struct tags, or a literal value to bind.
+type FuncArgKind int
+
+const (
+	ArgLiteral FuncArgKind = iota
+	ArgField
+)
+
+// FuncEmitter renders a single OData function call into dialect-specific
+// SQL. field is the already-resolved SQL column name for the function's
+// leading field argument; litArgs holds its remaining arguments, already
+// resolved per their ArgKind (raw lexer literal tokens, or resolved field
+// names). Implementations that bind a literal append its converted value
+// to args and use b.Dialect.Placeholder for its marker.
+type FuncEmitter func(b *SQLBuilder, field string, litArgs []string, args *[]any) (string, error)
+
+// FuncSpec describes one registered OData function: the kind of each
+// argument following the leading field, and how to emit it per dialect.
+type FuncSpec struct {
+	ArgKinds []FuncArgKind
+	Dialects map[string]FuncEmitter // keyed by Dialect.Name()
+}
+
+// FuncRegistry maps OData function names, as used in $filter, to their
+// FuncSpec. Callers may register additional functions (e.g. "geo.distance")
+// at init time.
+var FuncRegistry = map[string]FuncSpec{
+	"contains":   {ArgKinds: []FuncArgKind{ArgLiteral}, Dialects: likeFuncDialects("%%%s%%")},
+	"startswith": {ArgKinds: []FuncArgKind{ArgLiteral}, Dialects: likeFuncDialects("%s%%")},
+	"endswith":   {ArgKinds: []FuncArgKind{ArgLiteral}, Dialects: likeFuncDialects("%%%s")},
+	"tolower":    {Dialects: wrapFuncDialects("LOWER")},
+	"toupper":    {Dialects: wrapFuncDialects("UPPER")},
+	"year":       {Dialects: datePartFuncDialects("YEAR")},
+	"month":      {Dialects: datePartFuncDialects("MONTH")},
+	"day":        {Dialects: datePartFuncDialects("DAY")},
+}
+
+// renderFunc looks up name in FuncRegistry and emits it for b's dialect.
+func (b *SQLBuilder) renderFunc(name, field string, litArgs []string, args *[]any) (string, error) {
+	spec, ok := FuncRegistry[name]
+	if !ok {
+		return "", fmt.Errorf("unknown function %q: %w", name, ErrInvalidQuery)
+	}
+
+	emit, ok := spec.Dialects[b.Dialect.Name()]
+	if !ok {
+		return "", fmt.Errorf("function %q is not supported by dialect %q: %w", name, b.Dialect.Name(), ErrInvalidQuery)
+	}
+
+	return emit(b, field, litArgs, args)
+}
+
+// wrapFuncDialects builds identical per-dialect emitters for a plain
+// single-argument SQL function call, e.g. LOWER(field).
+func wrapFuncDialects(sqlFunc string) map[string]FuncEmitter {
+	emit := func(_ *SQLBuilder, field string, _ []string, _ *[]any) (string, error) {
+		return fmt.Sprintf("%s(%s)", sqlFunc, field), nil
+	}
+
+	return map[string]FuncEmitter{
+		"postgres":  emit,
+		"mysql":     emit,
+		"sqlite":    emit,
+		"sqlserver": emit,
+	}
+}
+
+// datePartFuncDialects builds the per-dialect emitters for a date-part
+// extraction function (year/month/day).
+func datePartFuncDialects(part string) map[string]FuncEmitter {
+	extract := func(_ *SQLBuilder, field string, _ []string, _ *[]any) (string, error) {
+		return fmt.Sprintf("EXTRACT(%s FROM %s)", part, field), nil
+	}
+
+	return map[string]FuncEmitter{
+		"postgres": extract,
+		"sqlite":   extract,
+		"mysql": func(_ *SQLBuilder, field string, _ []string, _ *[]any) (string, error) {
+			return fmt.Sprintf("%s(%s)", part, field), nil
+		},
+		"sqlserver": func(_ *SQLBuilder, field string, _ []string, _ *[]any) (string, error) {
+			return fmt.Sprintf("DATEPART(%s, %s)", part, field), nil
+		},
+	}
+}
+
+// likeEscapeChar is the ESCAPE character used to neutralize a user value's
+// own LIKE/ILIKE metacharacters before it is wrapped in wildcards, so e.g.
+// a literal `%` in the searched-for text isn't itself treated as a wildcard.
+const likeEscapeChar = '\\'
+
+// escapeLike escapes occurrences of escape, `%` and `_` in s with escape,
+// per the standard SQL ESCAPE clause convention.
+func escapeLike(s string, escape byte) string {
+	esc := string(escape)
+
+	s = strings.ReplaceAll(s, esc, esc+esc)
+	s = strings.ReplaceAll(s, "%", esc+"%")
+	s = strings.ReplaceAll(s, "_", esc+"_")
+
+	return s
+}
+
+// likeFuncDialects builds the per-dialect emitters for a LIKE/ILIKE-based
+// predicate function (contains/startswith/endswith). pattern is an fmt verb
+// placing the bound literal relative to the wildcard `%` characters, e.g.
+// "%%%s%%" for contains. The literal is escaped before being wrapped, and
+// an ESCAPE clause is emitted, so the value's own `%`/`_` match literally
+// rather than as wildcards.
+func likeFuncDialects(pattern string) map[string]FuncEmitter {
+	emit := func(op string) FuncEmitter {
+		return func(b *SQLBuilder, field string, litArgs []string, args *[]any) (string, error) {
+			if len(litArgs) != 1 {
+				return "", fmt.Errorf("expected exactly one literal argument: %w", ErrInvalidQuery)
+			}
+
+			val, err := convertLiteral(litArgs[0])
+			if err != nil {
+				return "", err
+			}
+
+			s, ok := val.(string)
+			if !ok {
+				return "", fmt.Errorf("expected string argument, got %T: %w", val, ErrInvalidQuery)
+			}
+
+			*args = append(*args, fmt.Sprintf(pattern, escapeLike(s, likeEscapeChar)))
+
+			return fmt.Sprintf("%s %s %s ESCAPE '%c'", field, op, b.Dialect.Placeholder(len(*args)), likeEscapeChar), nil
+		}
+	}
+
+	return map[string]FuncEmitter{
+		"postgres":  emit("ILIKE"),
+		"sqlite":    emit("LIKE"),
+		"mysql":     emit("LIKE"),
+		"sqlserver": emit("LIKE"),
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import "testing"
+
+func TestParser_FuncCallAsPredicate(t *testing.T) {
+	expr, err := parse(t, "contains(Title,'dune')")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	call, ok := expr.(*FuncCall)
+	if !ok {
+		t.Fatalf("expected *FuncCall, got %T", expr)
+	}
+
+	if call.Name != "contains" || call.Field != "title" || len(call.Args) != 1 || call.Args[0] != "'dune'" {
+		t.Fatalf("unexpected FuncCall: %+v", call)
+	}
+}
+
+func TestParser_FuncCallAsComparisonLHS(t *testing.T) {
+	expr, err := parse(t, "year(Published) eq 2023")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	cmp, ok := expr.(*Comparison)
+	if !ok {
+		t.Fatalf("expected *Comparison, got %T", expr)
+	}
+
+	if cmp.Func != "year" || cmp.Field != "published" || cmp.Op != "=" || cmp.Value != "2023" {
+		t.Fatalf("unexpected Comparison: %+v", cmp)
+	}
+}
+
+func TestRenderFunc_ContainsEscapesLikeMetacharacters(t *testing.T) {
+	b := NewSQLBuilder(PostgresDialect{})
+
+	var args []any
+
+	sql, err := b.renderFunc("contains", "title", []string{"'50%'"}, &args)
+	if err != nil {
+		t.Fatalf("renderFunc: %v", err)
+	}
+
+	wantSQL := "title ILIKE $1 ESCAPE '\\'"
+	if sql != wantSQL {
+		t.Fatalf("sql = %q, want %q", sql, wantSQL)
+	}
+
+	wantArg := `%50\%%`
+	if len(args) != 1 || args[0] != wantArg {
+		t.Fatalf("args = %#v, want [%q]", args, wantArg)
+	}
+}
+
+func TestRenderFunc_StartswithEscapesUnderscore(t *testing.T) {
+	b := NewSQLBuilder(MySQLDialect{})
+
+	var args []any
+
+	if _, err := b.renderFunc("startswith", "title", []string{"'a_b'"}, &args); err != nil {
+		t.Fatalf("renderFunc: %v", err)
+	}
+
+	wantArg := `a\_b%`
+	if len(args) != 1 || args[0] != wantArg {
+		t.Fatalf("args = %#v, want [%q]", args, wantArg)
+	}
+}
+
+func TestRenderFunc_DatePartPerDialect(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		want    string
+	}{
+		{PostgresDialect{}, "EXTRACT(YEAR FROM published)"},
+		{SQLiteDialect{}, "EXTRACT(YEAR FROM published)"},
+		{MySQLDialect{}, "YEAR(published)"},
+		{SQLServerDialect{}, "DATEPART(YEAR, published)"},
+	}
+
+	for _, tt := range tests {
+		b := NewSQLBuilder(tt.dialect)
+
+		var args []any
+
+		got, err := b.renderFunc("year", "published", nil, &args)
+		if err != nil {
+			t.Fatalf("renderFunc(%s): %v", tt.dialect.Name(), err)
+		}
+
+		if got != tt.want {
+			t.Errorf("renderFunc(%s) = %q, want %q", tt.dialect.Name(), got, tt.want)
+		}
+
+		if len(args) != 0 {
+			t.Errorf("renderFunc(%s) bound args %v, want none", tt.dialect.Name(), args)
+		}
+	}
+}
+
+func TestRenderFunc_UnknownFunction(t *testing.T) {
+	b := NewSQLBuilder(PostgresDialect{})
+
+	var args []any
+
+	if _, err := b.renderFunc("geo.distance", "title", nil, &args); err == nil {
+		t.Fatal("expected error for unregistered function")
+	}
+}
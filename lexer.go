@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// TokenType identifies the lexical class of a Token.
+type TokenType int
+
+const (
+	TokenEOF TokenType = iota
+	TokenIdent
+	TokenString
+	TokenNumber
+	TokenOp
+	TokenLParen
+	TokenRParen
+	TokenAnd
+	TokenOr
+	TokenNot
+	TokenIn
+	TokenComma
+)
+
+// Token is a single lexical unit produced by Lexer.
+type Token struct {
+	Type  TokenType
+	Value string
+}
+
+// Lexer scans a $filter expression rune by rune, emitting Tokens.
+// It replaces the previous single-regex approach so the parser can
+// support grouping, precedence and negation.
+type Lexer struct {
+	input []rune
+	pos   int
+}
+
+// NewLexer creates a Lexer over the given $filter expression.
+func NewLexer(input string) *Lexer {
+	return &Lexer{input: []rune(input)}
+}
+
+func (l *Lexer) peek() rune {
+	if l.pos >= len(l.input) {
+		return 0
+	}
+
+	return l.input[l.pos]
+}
+
+func (l *Lexer) peekAt(offset int) rune {
+	if l.pos+offset >= len(l.input) {
+		return 0
+	}
+
+	return l.input[l.pos+offset]
+}
+
+func (l *Lexer) advance() rune {
+	r := l.peek()
+	l.pos++
+
+	return r
+}
+
+func (l *Lexer) skipSpace() {
+	for l.pos < len(l.input) && unicode.IsSpace(l.input[l.pos]) {
+		l.pos++
+	}
+}
+
+// Next returns the next Token in the input, or a TokenEOF Token
+// once the input is exhausted.
+func (l *Lexer) Next() (Token, error) {
+	l.skipSpace()
+
+	if l.pos >= len(l.input) {
+		return Token{Type: TokenEOF}, nil
+	}
+
+	switch r := l.peek(); {
+	case r == '(':
+		l.advance()
+		return Token{Type: TokenLParen, Value: "("}, nil
+	case r == ')':
+		l.advance()
+		return Token{Type: TokenRParen, Value: ")"}, nil
+	case r == ',':
+		l.advance()
+		return Token{Type: TokenComma, Value: ","}, nil
+	case r == '\'':
+		return l.lexString()
+	case unicode.IsDigit(r) || (r == '-' && unicode.IsDigit(l.peekAt(1))):
+		return l.lexNumber()
+	case isIdentStart(r):
+		return l.lexIdent()
+	default:
+		return Token{}, fmt.Errorf("unexpected character %q: %w", r, ErrInvalidQuery)
+	}
+}
+
+func isIdentStart(r rune) bool {
+	return unicode.IsLetter(r) || r == '_'
+}
+
+func isIdentPart(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.'
+}
+
+// lexString scans a single-quoted OData string literal, where a doubled
+// quote ('') is the escape sequence for a literal quote.
+func (l *Lexer) lexString() (Token, error) {
+	l.advance() // opening quote
+
+	var sb strings.Builder
+	for {
+		if l.pos >= len(l.input) {
+			return Token{}, fmt.Errorf("unterminated string literal: %w", ErrInvalidQuery)
+		}
+
+		r := l.advance()
+		if r == '\'' {
+			if l.peek() == '\'' {
+				sb.WriteRune(l.advance())
+				continue
+			}
+
+			break
+		}
+
+		sb.WriteRune(r)
+	}
+
+	return Token{Type: TokenString, Value: "'" + sb.String() + "'"}, nil
+}
+
+func (l *Lexer) lexNumber() (Token, error) {
+	start := l.pos
+
+	if l.peek() == '-' {
+		l.advance()
+	}
+
+	for l.pos < len(l.input) && (unicode.IsDigit(l.input[l.pos]) || l.input[l.pos] == '.') {
+		l.advance()
+	}
+
+	return Token{Type: TokenNumber, Value: string(l.input[start:l.pos])}, nil
+}
+
+func (l *Lexer) lexIdent() (Token, error) {
+	start := l.pos
+
+	for l.pos < len(l.input) && isIdentPart(l.input[l.pos]) {
+		l.advance()
+	}
+
+	word := string(l.input[start:l.pos])
+
+	switch strings.ToLower(word) {
+	case "and":
+		return Token{Type: TokenAnd, Value: word}, nil
+	case "or":
+		return Token{Type: TokenOr, Value: word}, nil
+	case "not":
+		return Token{Type: TokenNot, Value: word}, nil
+	case "in":
+		return Token{Type: TokenIn, Value: word}, nil
+	case "eq", "ne", "gt", "lt", "gte", "lte":
+		return Token{Type: TokenOp, Value: word}, nil
+	default:
+		return Token{Type: TokenIdent, Value: word}, nil
+	}
+}
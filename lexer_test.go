@@ -0,0 +1,104 @@
+package main
+
+import "testing"
+
+func TestLexer_Next(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []Token
+	}{
+		{
+			name:  "comparison",
+			input: "Rate gt 100",
+			want: []Token{
+				{Type: TokenIdent, Value: "Rate"},
+				{Type: TokenOp, Value: "gt"},
+				{Type: TokenNumber, Value: "100"},
+				{Type: TokenEOF},
+			},
+		},
+		{
+			name:  "quoted string with escaped quote",
+			input: "'O''Brien'",
+			want: []Token{
+				{Type: TokenString, Value: "'O'Brien'"},
+				{Type: TokenEOF},
+			},
+		},
+		{
+			name:  "grouping and boolean keywords",
+			input: "(A eq 1) and not B eq 2",
+			want: []Token{
+				{Type: TokenLParen, Value: "("},
+				{Type: TokenIdent, Value: "A"},
+				{Type: TokenOp, Value: "eq"},
+				{Type: TokenNumber, Value: "1"},
+				{Type: TokenRParen, Value: ")"},
+				{Type: TokenAnd, Value: "and"},
+				{Type: TokenNot, Value: "not"},
+				{Type: TokenIdent, Value: "B"},
+				{Type: TokenOp, Value: "eq"},
+				{Type: TokenNumber, Value: "2"},
+				{Type: TokenEOF},
+			},
+		},
+		{
+			name:  "in list with comma",
+			input: "Id in (1,2)",
+			want: []Token{
+				{Type: TokenIdent, Value: "Id"},
+				{Type: TokenIn, Value: "in"},
+				{Type: TokenLParen, Value: "("},
+				{Type: TokenNumber, Value: "1"},
+				{Type: TokenComma, Value: ","},
+				{Type: TokenNumber, Value: "2"},
+				{Type: TokenRParen, Value: ")"},
+				{Type: TokenEOF},
+			},
+		},
+		{
+			name:  "negative number",
+			input: "Rate gt -5",
+			want: []Token{
+				{Type: TokenIdent, Value: "Rate"},
+				{Type: TokenOp, Value: "gt"},
+				{Type: TokenNumber, Value: "-5"},
+				{Type: TokenEOF},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLexer(tt.input)
+
+			for i, want := range tt.want {
+				got, err := l.Next()
+				if err != nil {
+					t.Fatalf("token %d: unexpected error: %v", i, err)
+				}
+
+				if got != want {
+					t.Fatalf("token %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLexer_UnterminatedString(t *testing.T) {
+	l := NewLexer("'unterminated")
+
+	if _, err := l.Next(); err == nil {
+		t.Fatal("expected error for unterminated string literal")
+	}
+}
+
+func TestLexer_UnexpectedCharacter(t *testing.T) {
+	l := NewLexer("#")
+
+	if _, err := l.Next(); err == nil {
+		t.Fatal("expected error for unexpected character")
+	}
+}
@@ -5,9 +5,7 @@ import (
 	"fmt"
 	"log"
 	"reflect"
-	"regexp"
 	"strconv"
-	"strings"
 )
 
 const (
@@ -18,65 +16,67 @@ const (
 )
 
 const defaultTagName = "sql"
+const expandTagName = "odata"
 
 var ErrInvalidQuery = errors.New("invalid OData query")
 
 // DataFilter build on top of OData filter query options:
-// $filter. represents filter which supports operations: `and`, `or`, `eq`, `ne`, `gt`, `lt`, `gte`, `lte`.
-// Not yet supports following properties: `from`, `to` (in UTC format), `in` Sequences (ids of sequences).
-// $orderby. optional param, represents sorting column which supports `acs` and `desc` operators.
+// $filter. represents filter which supports operations: `and`, `or`, `not`, `eq`, `ne`, `gt`, `lt`, `gte`, `lte`, `in`,
+// with parenthesized groups overriding the default `or` < `and` < `not` < comparison precedence. `in` also accepts
+// the ecosystem convention of repeated query-string keys on fields registered via RegisterArrayField, e.g.
+// `?Genre=Thriller&Genre=Horror`, and a repeated `$filter[]=` option that is AND-joined across occurrences.
+// Functions registered in FuncRegistry may also be used, e.g. `contains`, `startswith`, `endswith`,
+// `tolower`, `toupper`, `year`, `month`, `day`.
+// Not yet supports following properties: `from`, `to` (in UTC format).
+// $orderby. optional param, represents one or more sort keys, e.g. `Title desc,Rate asc`. The Harbor-style
+// `sort=-title,+rate` shorthand is also accepted as an alias, where a leading `-` means descending and
+// `+` or no sign means ascending.
 // $top. optional param, represents limit of items from the resource.
 // $skip. optional param, represents offset of records in the resource.
+// $select. optional param, represents a projection, e.g. `Title,Rate,Author/Name`. SQLBuilder.Build
+// does not apply it; render it into a column list with BuildSelect.
+// $expand. optional param, represents a relationship traversal, e.g. `Author,Reviews($top=5;$filter=Rating gt 3)`,
+// where each relation must carry an `odata:"expand=table,on=local_key=foreign_key"` struct tag.
+// SQLBuilder.Build does not apply it either; render each relation's JOIN or json_agg subquery with
+// BuildExpand, passing it the same args slice Build produced so placeholder numbering stays global,
+// and splice the result into the caller's own FROM clause or column list, per dialect.
 // Names of fields MUST correspond to struct field names.
-// Example: /books?$filter=Rate lt 100 and Rate gte 400 and Genre eq 'Thriller'&$orderby=Title desc&$top=100&$skip=10
+// Example: /books?$filter=Rate lt 100 and (Rate gte 400 or Genre eq 'Thriller')&$orderby=Title desc&$top=100&$skip=10
 type DataFilter struct {
-	Filter  *Filter
-	OrderBy *string
+	Filter  Expr
+	OrderBy []SortKey
 	Top     *int
 	Skip    *int
-}
-
-// Filter represent linked lists of OData expressions.
-type Filter struct {
-	Head *FilterNode
-}
+	Select  []FieldPath
+	Expand  []ExpandItem
 
-// FilterNode represents OData expression.
-type FilterNode struct {
-	Field       string
-	Operator    string
-	Conjunction string
-	Value       string
-	Next        *FilterNode
+	// arrayFields holds the fields registered via RegisterArrayField that
+	// opt into the repeated-key array shorthand, e.g. `?Genre=A&Genre=B`.
+	arrayFields map[string]bool
 }
 
 type fieldData map[string]string
 
-// Insert adds new expression to Filter chain.
-func (f *Filter) Insert(new *FilterNode) {
-	if f.Head == nil {
-		f.Head = new
-		return
-	}
-
-	node := f.Head
-	for node.Next != nil {
-		node = node.Next
-	}
-
-	node.Next = new
-}
-
 // ParseURL parse URL to OData filter friendly format.
 func (f *DataFilter) ParseURL(url string, src any) error {
-	data, err := getStructFieldData(src)
+	data, expand, err := getStructFieldData(src)
+	if err != nil {
+		return err
+	}
 
 	filter, err := parseFilter(url, data)
 	if err != nil {
 		return err
 	}
 
-	orderBy, err := parseOrderBy(url, data)
+	arrayFilter, err := parseArrayFilters(url, data, f.arrayFields)
+	if err != nil {
+		return err
+	}
+
+	filter = combineFilters(filter, arrayFilter)
+
+	orderBy, err := parseSortKeys(url, data)
 	if err != nil {
 		return err
 	}
@@ -91,55 +91,50 @@ func (f *DataFilter) ParseURL(url string, src any) error {
 		return err
 	}
 
+	sel, err := parseSelect(url, data, expand)
+	if err != nil {
+		return err
+	}
+
+	exp, err := parseExpand(url, data, expand)
+	if err != nil {
+		return err
+	}
+
 	f.Filter = filter
 	f.OrderBy = orderBy
 	f.Top = top
 	f.Skip = skip
+	f.Select = sel
+	f.Expand = exp
 
 	return nil
 }
 
+// EvaluateQuery delegates to SQLBuilder.Build against the default Postgres
+// dialect, returning just the SQL half and discarding its bound args. That
+// makes it unsafe to use with any filter that binds literals (its
+// placeholders are left unbound in the returned string), but it keeps
+// existing callers of this pre-SQLBuilder method working rather than
+// breaking them outright.
+//
+// Deprecated: use SQLBuilder.Build with an explicit Dialect, which returns
+// both the parameterized SQL and its bound args.
 func (f *DataFilter) EvaluateQuery() string {
-	var query string = "WHERE "
-
-	eval := func(node *FilterNode) string {
-		return fmt.Sprintf("%v%v%v %v ",
-			node.Field,
-			node.Operator,
-			node.Value,
-			node.Conjunction,
-		)
-	}
-
-	node := f.Filter.Head
-	for node.Next != nil {
-		query += eval(node)
-		node = node.Next
-	}
-
-	query += eval(node)
-
-	if f.OrderBy != nil {
-		query = fmt.Sprintf("%v\nORDER BY %v", query, *f.OrderBy)
-	}
-
-	if f.Skip != nil {
-		query = fmt.Sprintf("%v\nOFFSET %v", query, *f.Skip)
-	}
-
-	if f.Top != nil {
-		query = fmt.Sprintf("%v\nLIMIT %v", query, *f.Top)
+	sql, _, err := NewSQLBuilder(PostgresDialect{}).Build(f)
+	if err != nil {
+		return ""
 	}
 
-	return query
+	return sql
 }
 
 // parseQueryOption parses value of given QueryOption from URL query parameters.
 func parseQueryOption(query, opt string) string {
-	pattern := fmt.Sprintf(`(?P<option>\%s=)(?P<value>[^&$]*)`, opt)
-	if match := regexp.MustCompile(pattern).
-		FindStringSubmatch(query); match != nil {
-		return match[2]
+	for _, p := range splitQueryPairs(query) {
+		if p.key == opt {
+			return p.value
+		}
 	}
 
 	return ""
@@ -173,52 +168,9 @@ func parseTop(url string) (*int, error) {
 	return &val, nil
 }
 
-func parseOrderBy(url string, fieldMap fieldData) (*string, error) {
-	query := parseQueryOption(url, orderBy)
-	if query == "" {
-		return nil, nil
-	}
-
-	sortMap := map[string]string{
-		"asc":  "ASC",
-		"desc": "DESC",
-		"ASC":  "ASC",
-		"DESC": "DESC",
-	}
-
-	var fieldList, sortList []string
-
-	for k, v := range fieldMap {
-		fieldList = append(fieldList, k, v)
-	}
-
-	for k, v := range sortMap {
-		sortList = append(sortList, v, k)
-	}
-
-	pattern := fmt.Sprintf(`(%s)(\s(%s))*,*`,
-		strings.Join(fieldList, "|"),
-		strings.Join(sortList, "|"),
-	)
-
-	re := regexp.MustCompile(pattern)
-
-	if match := re.ReplaceAllLiteralString(query, ""); strings.TrimSpace(match) != "" {
-		return nil, fmt.Errorf("query does not correspond pattern: %s: %w", pattern, ErrInvalidQuery)
-	}
-
-	for k, v := range fieldMap {
-		query = strings.Replace(query, k, v, -1)
-	}
-
-	for k, v := range sortMap {
-		query = strings.Replace(query, k, v, -1)
-	}
-
-	return &query, nil
-}
-
-func parseFilter(url string, fieldMap fieldData) (*Filter, error) {
+// parseFilter parses the $filter query option into an Expr tree using
+// the lexer/parser pipeline, validating field names against fieldMap.
+func parseFilter(url string, fieldMap fieldData) (Expr, error) {
 	query := parseQueryOption(url, filter)
 	if query == "" {
 		return nil, nil
@@ -226,79 +178,24 @@ func parseFilter(url string, fieldMap fieldData) (*Filter, error) {
 
 	log.Printf("`%v`", query)
 
-	operMap := map[string]string{
-		"eq":  "=",
-		"ne":  "!=",
-		"gt":  ">",
-		"lt":  "<",
-		"lte": "<=",
-		"gte": ">=",
-	}
-
-	conjMap := map[string]string{
-		"and": "AND",
-		"or":  "OR",
-	}
-
-	var operList, conjList, fieldList []string
-
-	for k, v := range fieldMap {
-		fieldList = append(fieldList, k, v)
-	}
-
-	for k := range operMap {
-		operList = append(operList, k)
-	}
-
-	for k := range conjMap {
-		conjList = append(conjList, k)
-	}
-
-	pattern := fmt.Sprintf(`(?P<field>%s)\s+(?P<operator>%s)\s+(?P<value>\d+|'[^']+')\s*(?P<conjunction>%s)*\s*`,
-		strings.Join(fieldList, "|"),
-		strings.Join(operList, "|"),
-		strings.Join(conjList, "|"),
-	)
-
-	re := regexp.MustCompile(pattern)
-
-	if match := re.ReplaceAllLiteralString(query, ""); strings.TrimSpace(match) != "" {
-		return nil, fmt.Errorf("query does not correspond pattern: %s: %w", pattern, ErrInvalidQuery)
-	}
-
-	matches := re.FindAllStringSubmatch(query, -1)
-	groups := re.SubexpNames()
-
-	var fil = new(Filter)
-	for _, match := range matches {
-		var node FilterNode
-		for i := 1; i < len(groups); i++ {
-			switch groups[i] {
-			case "field":
-				node.Field = fieldMap[match[i]]
-			case "operator":
-				node.Operator = operMap[match[i]]
-			case "value":
-				node.Value = match[i]
-			case "conjunction":
-				node.Conjunction = conjMap[match[i]]
-			}
-		}
-
-		fil.Insert(&node)
+	p, err := NewParser(query, fieldMap)
+	if err != nil {
+		return nil, err
 	}
 
-	return fil, nil
+	return p.Parse()
 }
 
-// getStructFieldData retrieves list of struct field names
-// and their tag according to given tag name.
-func getStructFieldData(src any) (fieldData, error) {
-	var res = make(map[string]string, 0)
+// getStructFieldData retrieves list of struct field names and their tag
+// according to given tag name, along with expand metadata recorded via the
+// `odata` struct tag (e.g. `odata:"expand=authors,on=author_id=id"`).
+func getStructFieldData(src any) (fieldData, expandData, error) {
+	var res = make(fieldData)
+	var exp = make(expandData)
 
 	srcValue := reflect.Indirect(reflect.ValueOf(src))
 	if srcType := srcValue.Kind(); srcType != reflect.Struct {
-		return nil, fmt.Errorf("input value must be struct, got: %v", srcType)
+		return nil, nil, fmt.Errorf("input value must be struct, got: %v", srcType)
 	}
 
 	// iterate struct fields.
@@ -320,19 +217,32 @@ func getStructFieldData(src any) (fieldData, error) {
 		// add FieldName and value of defaultTagName.
 		res[fieldName] = tagValue
 
-		// recursive call for nested structs.
-		if fieldValue.Type().Kind() != reflect.Struct {
+		if meta, ok := parseExpandTag(tag.Get(expandTagName)); ok {
+			exp[fieldName] = meta
+		}
+
+		// recursive call for nested structs, including slice/array-typed
+		// one-to-many relations (e.g. `Reviews []Review`).
+		elemType := fieldValue.Type()
+		if k := elemType.Kind(); k == reflect.Slice || k == reflect.Array {
+			elemType = elemType.Elem()
+		}
+
+		if elemType.Kind() != reflect.Struct {
 			continue
 		}
 
-		nested, err := getStructFieldData(fieldValue.Interface())
+		nestedFields, nestedExpand, err := getStructFieldData(reflect.New(elemType).Elem().Interface())
 		if err != nil {
-			return nil, fmt.Errorf("error validating nested struct: %w", err)
+			return nil, nil, fmt.Errorf("error validating nested struct: %w", err)
 		}
-		for k, v := range nested {
+		for k, v := range nestedFields {
 			res[k] = v
 		}
+		for k, v := range nestedExpand {
+			exp[k] = v
+		}
 	}
 
-	return res, nil
+	return res, exp, nil
 }
@@ -0,0 +1,76 @@
+package main
+
+import "testing"
+
+type testReview struct {
+	Rating int `sql:"rating"`
+}
+
+type testBook struct {
+	Title   string       `sql:"title"`
+	Rate    float64      `sql:"rate"`
+	Reviews []testReview `sql:"reviews" odata:"expand=reviews,on=id=book_id"`
+}
+
+func TestGetStructFieldData_RecursesIntoSliceRelation(t *testing.T) {
+	fields, expand, err := getStructFieldData(testBook{})
+	if err != nil {
+		t.Fatalf("getStructFieldData: %v", err)
+	}
+
+	if _, ok := fields["Rating"]; !ok {
+		t.Fatalf("expected Reviews' nested field %q to be merged into fieldMap, got %v", "Rating", fields)
+	}
+
+	if _, ok := expand["Reviews"]; !ok {
+		t.Fatalf("expected expand metadata for %q, got %v", "Reviews", expand)
+	}
+}
+
+func TestParseURL_ExpandNestedOptionsDoNotLeakIntoOuterParsers(t *testing.T) {
+	url := "/books?$top=100&$skip=10&$expand=Reviews($top=5;$filter=Rating gt 3)"
+
+	var f DataFilter
+	if err := f.ParseURL(url, testBook{}); err != nil {
+		t.Fatalf("ParseURL: %v", err)
+	}
+
+	if f.Top == nil || *f.Top != 100 {
+		t.Fatalf("expected outer $top=100 to survive, got %v", f.Top)
+	}
+
+	if f.Skip == nil || *f.Skip != 10 {
+		t.Fatalf("expected outer $skip=10 to survive, got %v", f.Skip)
+	}
+
+	if len(f.Expand) != 1 {
+		t.Fatalf("expected one expand item, got %d", len(f.Expand))
+	}
+
+	item := f.Expand[0]
+	if item.Filter == nil || item.Filter.Top == nil || *item.Filter.Top != 5 {
+		t.Fatalf("expected nested $top=5 on expand item, got %+v", item.Filter)
+	}
+
+	if item.Filter.Filter == nil {
+		t.Fatalf("expected nested $filter on expand item to be parsed")
+	}
+}
+
+func TestParseQueryOptions_DoesNotMatchSubstringOfSiblingKey(t *testing.T) {
+	type nameStruct struct {
+		Name     string `sql:"name"`
+		LastName string `sql:"last_name"`
+	}
+
+	var f DataFilter
+	f.RegisterArrayField("Name")
+
+	if err := f.ParseURL("/people?LastName=Smith", nameStruct{}); err != nil {
+		t.Fatalf("ParseURL: %v", err)
+	}
+
+	if f.Filter != nil {
+		t.Fatalf("expected Name not to be matched against LastName's value, got filter %+v", f.Filter)
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const sortAlias = "sort"
+
+// SortKey is a single resolved $orderby/sort key. NullsFirst is nil unless
+// the caller explicitly wants nulls ordering, since neither the OData
+// `$orderby` syntax nor the `sort=` shorthand carries that information.
+type SortKey struct {
+	Field      string
+	Desc       bool
+	NullsFirst *bool
+}
+
+// parseSortKeys parses the $orderby (OData) query option, falling back to
+// the `sort=` shorthand alias, into a slice of SortKey. Every field name is
+// validated against fieldMap via lookupField, which, unlike $filter, also
+// accepts a case-insensitive match; unknown fields are rejected with
+// ErrInvalidQuery.
+func parseSortKeys(url string, fieldMap fieldData) ([]SortKey, error) {
+	if query := firstQueryOption(url, orderBy); query != "" {
+		return parseODataSort(query, fieldMap)
+	}
+
+	if query := firstQueryOption(url, sortAlias); query != "" {
+		return parseSortShorthand(query, fieldMap)
+	}
+
+	return nil, nil
+}
+
+// parseODataSort parses `Title desc,Rate asc`.
+func parseODataSort(query string, fieldMap fieldData) ([]SortKey, error) {
+	var keys []SortKey
+
+	for _, clause := range strings.Split(query, ",") {
+		parts := strings.Fields(strings.TrimSpace(clause))
+		if len(parts) == 0 {
+			continue
+		}
+
+		field, ok := lookupField(fieldMap, parts[0])
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q: %w", parts[0], ErrInvalidQuery)
+		}
+
+		key := SortKey{Field: field}
+
+		if len(parts) > 1 {
+			switch strings.ToLower(parts[1]) {
+			case "desc":
+				key.Desc = true
+			case "asc":
+			default:
+				return nil, fmt.Errorf("unknown sort direction %q: %w", parts[1], ErrInvalidQuery)
+			}
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// parseSortShorthand parses `-title,+rate`.
+func parseSortShorthand(query string, fieldMap fieldData) ([]SortKey, error) {
+	var keys []SortKey
+
+	for _, clause := range strings.Split(query, ",") {
+		clause = strings.TrimSpace(clause)
+		if clause == "" {
+			continue
+		}
+
+		var desc bool
+
+		name := clause
+		switch clause[0] {
+		case '-':
+			desc = true
+			name = clause[1:]
+		case '+':
+			name = clause[1:]
+		}
+
+		field, ok := lookupField(fieldMap, name)
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q: %w", name, ErrInvalidQuery)
+		}
+
+		keys = append(keys, SortKey{Field: field, Desc: desc})
+	}
+
+	return keys, nil
+}
+
+// lookupField resolves name against fieldMap, falling back to a
+// case-insensitive match so the sort= shorthand's lower-case convention
+// (e.g. "title") resolves against struct field names (e.g. "Title").
+func lookupField(fieldMap fieldData, name string) (string, bool) {
+	if field, ok := fieldMap[name]; ok {
+		return field, true
+	}
+
+	for k, v := range fieldMap {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+
+	return "", false
+}
+
+// firstQueryOption returns the first value bound to the query-string key
+// opt, or "" if absent. Like parseQueryOption, key matching is exact, so
+// opt need not start with `$`.
+func firstQueryOption(url, opt string) string {
+	vals := parseQueryOptions(url, opt)
+	if len(vals) == 0 {
+		return ""
+	}
+
+	return vals[0]
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSortKeys_ODataMultiKey(t *testing.T) {
+	keys, err := parseSortKeys("/books?$orderby=Title desc,Rate asc", testFields)
+	if err != nil {
+		t.Fatalf("parseSortKeys: %v", err)
+	}
+
+	want := []SortKey{
+		{Field: "title", Desc: true},
+		{Field: "rate", Desc: false},
+	}
+
+	if !reflect.DeepEqual(keys, want) {
+		t.Fatalf("keys = %+v, want %+v", keys, want)
+	}
+}
+
+func TestParseSortKeys_ShorthandAliasWithCaseInsensitiveLookup(t *testing.T) {
+	keys, err := parseSortKeys("/books?sort=-title,+rate", testFields)
+	if err != nil {
+		t.Fatalf("parseSortKeys: %v", err)
+	}
+
+	want := []SortKey{
+		{Field: "title", Desc: true},
+		{Field: "rate", Desc: false},
+	}
+
+	if !reflect.DeepEqual(keys, want) {
+		t.Fatalf("keys = %+v, want %+v", keys, want)
+	}
+}
+
+func TestParseSortKeys_ShorthandNoSignDefaultsAscending(t *testing.T) {
+	keys, err := parseSortKeys("/books?sort=rate", testFields)
+	if err != nil {
+		t.Fatalf("parseSortKeys: %v", err)
+	}
+
+	want := []SortKey{{Field: "rate", Desc: false}}
+	if !reflect.DeepEqual(keys, want) {
+		t.Fatalf("keys = %+v, want %+v", keys, want)
+	}
+}
+
+func TestParseSortKeys_UnknownFieldRejected(t *testing.T) {
+	if _, err := parseSortKeys("/books?$orderby=Bogus desc", testFields); err == nil {
+		t.Fatal("expected error for unknown sort field")
+	}
+}
+
+func TestParseSortKeys_AbsentReturnsNil(t *testing.T) {
+	keys, err := parseSortKeys("/books?$top=10", testFields)
+	if err != nil {
+		t.Fatalf("parseSortKeys: %v", err)
+	}
+
+	if keys != nil {
+		t.Fatalf("keys = %+v, want nil", keys)
+	}
+}
@@ -0,0 +1,419 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// operMap translates OData comparison keywords into SQL operators.
+var operMap = map[string]string{
+	"eq":  "=",
+	"ne":  "!=",
+	"gt":  ">",
+	"lt":  "<",
+	"lte": "<=",
+	"gte": ">=",
+}
+
+// Expr is an OData $filter expression AST node. Callers may type-switch
+// on the concrete node types to inspect or rewrite the tree.
+type Expr interface {
+	isExpr()
+}
+
+// AndExpr represents `Left and Right`.
+type AndExpr struct {
+	Left, Right Expr
+}
+
+// OrExpr represents `Left or Right`.
+type OrExpr struct {
+	Left, Right Expr
+}
+
+// NotExpr represents `not Expr`.
+type NotExpr struct {
+	Expr Expr
+}
+
+// Comparison represents a single `Field Op Value` term, e.g. `Rate gt 100`.
+// Func is set when Field is wrapped by a value-transform function, e.g.
+// `year(Published) eq 2023` or `tolower(Title) eq 'dune'`.
+type Comparison struct {
+	Field string
+	Op    string
+	Value string
+	Func  string
+}
+
+// InExpr represents `Field in (Values...)`, e.g. `Genre in ('Thriller','Horror')`.
+type InExpr struct {
+	Field  string
+	Values []string
+}
+
+// FuncCall represents a boolean predicate function applied directly as a
+// filter term, e.g. `contains(Title,'foo')`. Args holds the arguments
+// following the leading field argument: raw literal tokens for ArgLiteral
+// arguments, resolved column names for ArgField arguments.
+type FuncCall struct {
+	Name  string
+	Field string
+	Args  []string
+}
+
+func (*AndExpr) isExpr()    {}
+func (*OrExpr) isExpr()     {}
+func (*NotExpr) isExpr()    {}
+func (*Comparison) isExpr() {}
+func (*InExpr) isExpr()     {}
+func (*FuncCall) isExpr()   {}
+
+// Parser is a recursive-descent parser over the Tokens produced by Lexer.
+// Precedence, from loosest to tightest, is: or, and, not, comparison;
+// parentheses override precedence.
+type Parser struct {
+	lex    *Lexer
+	tok    Token
+	fields fieldData
+}
+
+// NewParser creates a Parser over the given $filter expression, validating
+// field names against fields.
+func NewParser(input string, fields fieldData) (*Parser, error) {
+	p := &Parser{lex: NewLexer(input), fields: fields}
+
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (p *Parser) next() error {
+	tok, err := p.lex.Next()
+	if err != nil {
+		return err
+	}
+
+	p.tok = tok
+
+	return nil
+}
+
+// Parse consumes the full input and returns the root Expr.
+func (p *Parser) Parse() (Expr, error) {
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.tok.Type != TokenEOF {
+		return nil, fmt.Errorf("unexpected token %q: %w", p.tok.Value, ErrInvalidQuery)
+	}
+
+	return expr, nil
+}
+
+func (p *Parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.tok.Type == TokenOr {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &OrExpr{Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *Parser) parseAnd() (Expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.tok.Type == TokenAnd {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &AndExpr{Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *Parser) parseNot() (Expr, error) {
+	if p.tok.Type == TokenNot {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		return &NotExpr{Expr: expr}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *Parser) parsePrimary() (Expr, error) {
+	switch p.tok.Type {
+	case TokenLParen:
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.tok.Type != TokenRParen {
+			return nil, fmt.Errorf("expected closing parenthesis: %w", ErrInvalidQuery)
+		}
+
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		return expr, nil
+	case TokenIdent:
+		next, err := p.peekNext()
+		if err != nil {
+			return nil, err
+		}
+
+		if next.Type == TokenLParen {
+			return p.parseFuncCall()
+		}
+
+		return p.parseComparison()
+	default:
+		return nil, fmt.Errorf("unexpected token %q: %w", p.tok.Value, ErrInvalidQuery)
+	}
+}
+
+// peekNext returns the token following the current one without consuming it,
+// by lexing from a copy of the lexer's position.
+func (p *Parser) peekNext() (Token, error) {
+	clone := *p.lex
+	return clone.Next()
+}
+
+// parseFuncCall parses an OData function call, e.g. `contains(Title,'foo')`
+// or `year(Published)`. When followed by a comparison operator, it lowers
+// to a Comparison with Func set; otherwise it is itself a boolean FuncCall.
+func (p *Parser) parseFuncCall() (Expr, error) {
+	name := strings.ToLower(p.tok.Value)
+
+	spec, ok := FuncRegistry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q: %w", name, ErrInvalidQuery)
+	}
+
+	if err := p.next(); err != nil { // consume function name
+		return nil, err
+	}
+
+	if err := p.next(); err != nil { // consume '('
+		return nil, err
+	}
+
+	if p.tok.Type != TokenIdent {
+		return nil, fmt.Errorf("expected field argument to %q, got %q: %w", name, p.tok.Value, ErrInvalidQuery)
+	}
+
+	field, ok := p.fields[p.tok.Value]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q: %w", p.tok.Value, ErrInvalidQuery)
+	}
+
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	args, err := p.parseFuncArgs(name, spec.ArgKinds)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.tok.Type != TokenRParen {
+		return nil, fmt.Errorf("expected ')' closing call to %q, got %q: %w", name, p.tok.Value, ErrInvalidQuery)
+	}
+
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	if p.tok.Type != TokenOp {
+		return &FuncCall{Name: name, Field: field, Args: args}, nil
+	}
+
+	op := operMap[strings.ToLower(p.tok.Value)]
+
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	if p.tok.Type != TokenString && p.tok.Type != TokenNumber {
+		return nil, fmt.Errorf("expected comparison value, got %q: %w", p.tok.Value, ErrInvalidQuery)
+	}
+
+	value := p.tok.Value
+
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	return &Comparison{Field: field, Op: op, Value: value, Func: name}, nil
+}
+
+// parseFuncArgs parses the trailing arguments of a function call following
+// the leading field, one comma-prefixed argument per kind in kinds.
+func (p *Parser) parseFuncArgs(name string, kinds []FuncArgKind) ([]string, error) {
+	var args []string
+
+	for _, kind := range kinds {
+		if p.tok.Type != TokenComma {
+			return nil, fmt.Errorf("expected ',' before argument to %q, got %q: %w", name, p.tok.Value, ErrInvalidQuery)
+		}
+
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		switch kind {
+		case ArgLiteral:
+			if p.tok.Type != TokenString && p.tok.Type != TokenNumber {
+				return nil, fmt.Errorf("expected literal argument to %q, got %q: %w", name, p.tok.Value, ErrInvalidQuery)
+			}
+
+			args = append(args, p.tok.Value)
+		case ArgField:
+			if p.tok.Type != TokenIdent {
+				return nil, fmt.Errorf("expected field argument to %q, got %q: %w", name, p.tok.Value, ErrInvalidQuery)
+			}
+
+			field, ok := p.fields[p.tok.Value]
+			if !ok {
+				return nil, fmt.Errorf("unknown field %q: %w", p.tok.Value, ErrInvalidQuery)
+			}
+
+			args = append(args, field)
+		}
+
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+
+	return args, nil
+}
+
+func (p *Parser) parseComparison() (Expr, error) {
+	if p.tok.Type != TokenIdent {
+		return nil, fmt.Errorf("expected field name, got %q: %w", p.tok.Value, ErrInvalidQuery)
+	}
+
+	field, ok := p.fields[p.tok.Value]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q: %w", p.tok.Value, ErrInvalidQuery)
+	}
+
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	if p.tok.Type == TokenIn {
+		return p.parseIn(field)
+	}
+
+	if p.tok.Type != TokenOp {
+		return nil, fmt.Errorf("expected comparison operator, got %q: %w", p.tok.Value, ErrInvalidQuery)
+	}
+
+	op := operMap[strings.ToLower(p.tok.Value)]
+
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	if p.tok.Type != TokenString && p.tok.Type != TokenNumber {
+		return nil, fmt.Errorf("expected comparison value, got %q: %w", p.tok.Value, ErrInvalidQuery)
+	}
+
+	value := p.tok.Value
+
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	return &Comparison{Field: field, Op: op, Value: value}, nil
+}
+
+// parseIn parses the `in (v1, v2, ...)` collection literal following a field name.
+func (p *Parser) parseIn(field string) (Expr, error) {
+	if err := p.next(); err != nil { // consume `in`
+		return nil, err
+	}
+
+	if p.tok.Type != TokenLParen {
+		return nil, fmt.Errorf("expected '(' after in, got %q: %w", p.tok.Value, ErrInvalidQuery)
+	}
+
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	var values []string
+
+	for {
+		if p.tok.Type != TokenString && p.tok.Type != TokenNumber {
+			return nil, fmt.Errorf("expected value in 'in' list, got %q: %w", p.tok.Value, ErrInvalidQuery)
+		}
+
+		values = append(values, p.tok.Value)
+
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		if p.tok.Type != TokenComma {
+			break
+		}
+
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.tok.Type != TokenRParen {
+		return nil, fmt.Errorf("expected ')' closing in list, got %q: %w", p.tok.Value, ErrInvalidQuery)
+	}
+
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	return &InExpr{Field: field, Values: values}, nil
+}
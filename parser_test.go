@@ -0,0 +1,109 @@
+package main
+
+import "testing"
+
+var testFields = fieldData{
+	"Rate":      "rate",
+	"Genre":     "genre",
+	"Title":     "title",
+	"Published": "published",
+}
+
+func TestParser_Precedence(t *testing.T) {
+	// "or" binds loosest, so this parses as (Rate gt 100) or (Genre eq 'X' and Title eq 'Y').
+	expr, err := parse(t, "Rate gt 100 or Genre eq 'X' and Title eq 'Y'")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	or, ok := expr.(*OrExpr)
+	if !ok {
+		t.Fatalf("expected root *OrExpr, got %T", expr)
+	}
+
+	if _, ok := or.Left.(*Comparison); !ok {
+		t.Fatalf("expected OrExpr.Left to be *Comparison, got %T", or.Left)
+	}
+
+	if _, ok := or.Right.(*AndExpr); !ok {
+		t.Fatalf("expected OrExpr.Right to be *AndExpr, got %T", or.Right)
+	}
+}
+
+func TestParser_ParenthesesOverridePrecedence(t *testing.T) {
+	expr, err := parse(t, "(Rate gt 100 or Genre eq 'X') and Title eq 'Y'")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	and, ok := expr.(*AndExpr)
+	if !ok {
+		t.Fatalf("expected root *AndExpr, got %T", expr)
+	}
+
+	if _, ok := and.Left.(*OrExpr); !ok {
+		t.Fatalf("expected AndExpr.Left to be *OrExpr, got %T", and.Left)
+	}
+}
+
+func TestParser_Not(t *testing.T) {
+	expr, err := parse(t, "not Rate gt 100")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	not, ok := expr.(*NotExpr)
+	if !ok {
+		t.Fatalf("expected root *NotExpr, got %T", expr)
+	}
+
+	if _, ok := not.Expr.(*Comparison); !ok {
+		t.Fatalf("expected NotExpr.Expr to be *Comparison, got %T", not.Expr)
+	}
+}
+
+func TestParser_In(t *testing.T) {
+	expr, err := parse(t, "Genre in ('Thriller','Horror','SciFi')")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	in, ok := expr.(*InExpr)
+	if !ok {
+		t.Fatalf("expected root *InExpr, got %T", expr)
+	}
+
+	want := []string{"'Thriller'", "'Horror'", "'SciFi'"}
+	if len(in.Values) != len(want) {
+		t.Fatalf("Values = %v, want %v", in.Values, want)
+	}
+
+	for i, v := range want {
+		if in.Values[i] != v {
+			t.Fatalf("Values[%d] = %q, want %q", i, in.Values[i], v)
+		}
+	}
+}
+
+func TestParser_UnknownFieldRejected(t *testing.T) {
+	if _, err := parse(t, "Unknown eq 1"); err == nil {
+		t.Fatal("expected error for unknown field")
+	}
+}
+
+func TestParser_UnclosedGroupRejected(t *testing.T) {
+	if _, err := parse(t, "(Rate gt 100"); err == nil {
+		t.Fatal("expected error for unclosed parenthesis")
+	}
+}
+
+func parse(t *testing.T, input string) (Expr, error) {
+	t.Helper()
+
+	p, err := NewParser(input, testFields)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Parse()
+}
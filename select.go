@@ -0,0 +1,267 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	selectOpt = "$select"
+	expandOpt = "$expand"
+)
+
+// FieldPath is one `$select` entry. Raw keeps the entry as written, e.g.
+// "Author/Name"; Segments holds its resolved SQL names, e.g.
+// ["authors", "name"] for a relation joined to the `authors` table.
+type FieldPath struct {
+	Raw      string
+	Segments []string
+}
+
+// expandMeta describes the join target of a relation, recorded via the
+// `odata` struct tag, e.g. `odata:"expand=authors,on=author_id=id"`.
+type expandMeta struct {
+	Table      string
+	LocalKey   string
+	ForeignKey string
+}
+
+// expandData maps a struct field name to its expandMeta, for fields that
+// carry an `odata:"expand=...,on=...=..."` tag.
+type expandData map[string]expandMeta
+
+// ExpandItem is one resolved `$expand` relation, e.g. `Reviews($top=5;$filter=Rating gt 3)`.
+type ExpandItem struct {
+	Relation   string
+	JoinTable  string
+	LocalKey   string
+	ForeignKey string
+	Filter     *DataFilter
+}
+
+// parseExpandTag parses the `odata` struct tag value into an expandMeta.
+// ok is false if tag is empty or carries no `expand=` clause.
+func parseExpandTag(tag string) (meta expandMeta, ok bool) {
+	if tag == "" {
+		return expandMeta{}, false
+	}
+
+	for _, part := range strings.Split(tag, ",") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		switch kv[0] {
+		case "expand":
+			meta.Table = kv[1]
+		case "on":
+			on := strings.SplitN(kv[1], "=", 2)
+			if len(on) == 2 {
+				meta.LocalKey, meta.ForeignKey = on[0], on[1]
+			}
+		}
+	}
+
+	return meta, meta.Table != ""
+}
+
+// parseSelect parses the $select query option into a slice of FieldPath. A
+// flat segment (no `/`) resolves against fieldMap, same as any plain field
+// reference. A `/`-joined path (e.g. `Author/Name`) instead traverses a
+// relation: its leading segment resolves against expand, whose expandMeta
+// carries the join table rather than the relation's own column tag (a
+// relation field's fieldMap entry is its foreign key, e.g. `author_id`, not
+// something a SELECT can qualify a column with); only the trailing
+// segment(s) resolve against fieldMap, as they name columns on the joined
+// table.
+func parseSelect(url string, fieldMap fieldData, expand expandData) ([]FieldPath, error) {
+	query := firstQueryOption(url, selectOpt)
+	if query == "" {
+		return nil, nil
+	}
+
+	var paths []FieldPath
+
+	for _, raw := range strings.Split(query, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		rawSegments := strings.Split(raw, "/")
+		segments := make([]string, len(rawSegments))
+
+		start := 0
+
+		if len(rawSegments) > 1 {
+			meta, ok := expand[rawSegments[0]]
+			if !ok {
+				return nil, fmt.Errorf("unknown relation %q: %w", rawSegments[0], ErrInvalidQuery)
+			}
+
+			segments[0] = meta.Table
+			start = 1
+		}
+
+		for i := start; i < len(rawSegments); i++ {
+			field, ok := lookupField(fieldMap, rawSegments[i])
+			if !ok {
+				return nil, fmt.Errorf("unknown field %q: %w", rawSegments[i], ErrInvalidQuery)
+			}
+
+			segments[i] = field
+		}
+
+		paths = append(paths, FieldPath{Raw: raw, Segments: segments})
+	}
+
+	return paths, nil
+}
+
+// BuildSelect renders sel as a dot-joined, comma-separated column list for
+// a caller's own SELECT clause (e.g. "title, author.name"), or "*" when sel
+// is empty. SQLBuilder.Build does not call this itself: it only renders
+// WHERE/ORDER BY/pagination, so callers compose BuildSelect's result into
+// their own SELECT ... FROM ... query.
+func BuildSelect(sel []FieldPath) string {
+	if len(sel) == 0 {
+		return "*"
+	}
+
+	cols := make([]string, len(sel))
+	for i, f := range sel {
+		cols[i] = strings.Join(f.Segments, ".")
+	}
+
+	return strings.Join(cols, ", ")
+}
+
+// parseExpand parses the $expand query option into a slice of ExpandItem,
+// e.g. `$expand=Author,Reviews($top=5;$filter=Rating gt 3)`. Each relation
+// name is validated against expand, which is populated from `odata` struct
+// tags by getStructFieldData.
+func parseExpand(url string, fieldMap fieldData, expand expandData) ([]ExpandItem, error) {
+	query := firstQueryOption(url, expandOpt)
+	if query == "" {
+		return nil, nil
+	}
+
+	var items []ExpandItem
+
+	for _, raw := range splitOutsideParens(query, ',') {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		item, err := parseExpandItem(raw, fieldMap, expand)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// parseExpandItem parses one `$expand` entry: a bare relation name, or a
+// relation name followed by its own parenthesized, `;`-separated query
+// options, e.g. `Reviews($top=5;$filter=Rating gt 3)`.
+func parseExpandItem(raw string, fieldMap fieldData, expand expandData) (ExpandItem, error) {
+	name := raw
+
+	var nested string
+
+	if idx := strings.Index(raw, "("); idx != -1 {
+		if !strings.HasSuffix(raw, ")") {
+			return ExpandItem{}, fmt.Errorf("unbalanced parentheses in expand item %q: %w", raw, ErrInvalidQuery)
+		}
+
+		name = strings.TrimSpace(raw[:idx])
+		nested = raw[idx+1 : len(raw)-1]
+	}
+
+	meta, ok := expand[name]
+	if !ok {
+		return ExpandItem{}, fmt.Errorf("unknown expand relation %q: %w", name, ErrInvalidQuery)
+	}
+
+	item := ExpandItem{
+		Relation:   name,
+		JoinTable:  meta.Table,
+		LocalKey:   meta.LocalKey,
+		ForeignKey: meta.ForeignKey,
+	}
+
+	if nested == "" {
+		return item, nil
+	}
+
+	filter, err := parseNestedFilter(nested, fieldMap)
+	if err != nil {
+		return ExpandItem{}, err
+	}
+
+	item.Filter = filter
+
+	return item, nil
+}
+
+// parseNestedFilter parses the `;`-separated query options carried inside an
+// `$expand` relation's parentheses, reusing the same field map as the
+// enclosing DataFilter.
+func parseNestedFilter(opts string, fieldMap fieldData) (*DataFilter, error) {
+	query := strings.ReplaceAll(opts, ";", "&")
+
+	filter, err := parseFilter(query, fieldMap)
+	if err != nil {
+		return nil, err
+	}
+
+	orderBy, err := parseSortKeys(query, fieldMap)
+	if err != nil {
+		return nil, err
+	}
+
+	top, err := parseTop(query)
+	if err != nil {
+		return nil, err
+	}
+
+	skip, err := parseSkip(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DataFilter{Filter: filter, OrderBy: orderBy, Top: top, Skip: skip}, nil
+}
+
+// splitOutsideParens splits s on sep, ignoring occurrences nested inside
+// parentheses, so a $expand option's own nested $filter can use commas
+// (e.g. an `in (...)` list) without being split.
+func splitOutsideParens(s string, sep byte) []string {
+	var parts []string
+
+	depth := 0
+	start := 0
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case sep:
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	parts = append(parts, s[start:])
+
+	return parts
+}
@@ -0,0 +1,78 @@
+package main
+
+import "testing"
+
+func TestParseSelect_ResolvesEverySegmentToSQLColumn(t *testing.T) {
+	type author struct {
+		Name string `sql:"name"`
+	}
+
+	type book struct {
+		Title  string `sql:"title"`
+		Author author `sql:"author_id"`
+	}
+
+	fields, expand, err := getStructFieldData(book{})
+	if err != nil {
+		t.Fatalf("getStructFieldData: %v", err)
+	}
+
+	paths, err := parseSelect("/books?$select=Title,Name", fields, expand)
+	if err != nil {
+		t.Fatalf("parseSelect: %v", err)
+	}
+
+	got := BuildSelect(paths)
+	want := "title, name"
+	if got != want {
+		t.Fatalf("BuildSelect() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildSelect_EmptyYieldsStar(t *testing.T) {
+	if got := BuildSelect(nil); got != "*" {
+		t.Fatalf("BuildSelect(nil) = %q, want %q", got, "*")
+	}
+}
+
+func TestParseSelect_ResolvesRelationSegmentToJoinTable(t *testing.T) {
+	type author struct {
+		Name string `sql:"name"`
+	}
+
+	type book struct {
+		Title  string `sql:"title"`
+		Author author `sql:"author_id" odata:"expand=authors,on=author_id=id"`
+	}
+
+	fields, expand, err := getStructFieldData(book{})
+	if err != nil {
+		t.Fatalf("getStructFieldData: %v", err)
+	}
+
+	paths, err := parseSelect("/books?$select=Author/Name", fields, expand)
+	if err != nil {
+		t.Fatalf("parseSelect: %v", err)
+	}
+
+	got := BuildSelect(paths)
+	want := "authors.name"
+	if got != want {
+		t.Fatalf("BuildSelect() = %q, want %q", got, want)
+	}
+}
+
+func TestParseSelect_UnknownRelationSegmentRejected(t *testing.T) {
+	type book struct {
+		Title string `sql:"title"`
+	}
+
+	fields, expand, err := getStructFieldData(book{})
+	if err != nil {
+		t.Fatalf("getStructFieldData: %v", err)
+	}
+
+	if _, err := parseSelect("/books?$select=Bogus/Name", fields, expand); err == nil {
+		t.Fatal("expected error for unknown relation segment")
+	}
+}
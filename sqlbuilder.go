@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SQLBuilder walks a DataFilter's Expr tree and renders parameterized SQL
+// for a specific Dialect, binding every literal as an arg rather than
+// interpolating it into the query string.
+type SQLBuilder struct {
+	Dialect Dialect
+}
+
+// NewSQLBuilder creates a SQLBuilder targeting the given Dialect.
+func NewSQLBuilder(d Dialect) *SQLBuilder {
+	return &SQLBuilder{Dialect: d}
+}
+
+// Build renders f into a SQL WHERE/ORDER BY/pagination clause, returning the
+// query string, its bound args in placeholder order, and any error
+// encountered while converting a literal. It does not incorporate f.Select
+// or f.Expand: those target different parts of a full query (the SELECT
+// column list and the FROM/JOIN clause respectively, and their shape
+// depends on dialect) than the WHERE-onward fragment Build returns. To
+// assemble a full query, render the column list with BuildSelect, then pass
+// the same args slice Build returned into BuildExpand by reference (e.g.
+// `sql, args, _ := b.Build(f); joins, _ := b.BuildExpand(f.Expand, &args)`)
+// so placeholder numbering carries on from where Build left off instead of
+// restarting at 1.
+func (b *SQLBuilder) Build(f *DataFilter) (string, []any, error) {
+	var sb strings.Builder
+
+	var args []any
+
+	if f.Filter != nil {
+		clause, err := b.buildExpr(f.Filter, &args)
+		if err != nil {
+			return "", nil, err
+		}
+
+		sb.WriteString("WHERE ")
+		sb.WriteString(clause)
+	}
+
+	if len(f.OrderBy) > 0 {
+		sb.WriteString("\nORDER BY ")
+		sb.WriteString(b.buildOrderBy(f.OrderBy))
+	}
+
+	sb.WriteString(b.Dialect.Paginate(f.Top, f.Skip))
+
+	return sb.String(), args, nil
+}
+
+// buildOrderBy renders a sort spec as a dialect-appropriate ORDER BY clause
+// body, e.g. "f1 DESC NULLS LAST, f2 ASC".
+func (b *SQLBuilder) buildOrderBy(keys []SortKey) string {
+	parts := make([]string, len(keys))
+
+	for i, k := range keys {
+		dir := "ASC"
+		if k.Desc {
+			dir = "DESC"
+		}
+
+		parts[i] = fmt.Sprintf("%s %s%s", k.Field, dir, b.Dialect.NullsClause(k.NullsFirst))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func (b *SQLBuilder) buildExpr(expr Expr, args *[]any) (string, error) {
+	switch e := expr.(type) {
+	case *Comparison:
+		return b.buildComparison(e, args)
+	case *InExpr:
+		return b.buildIn(e, args)
+	case *FuncCall:
+		return b.renderFunc(e.Name, e.Field, e.Args, args)
+	case *NotExpr:
+		inner, err := b.buildExpr(e.Expr, args)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("NOT (%s)", inner), nil
+	case *AndExpr:
+		left, err := b.buildExpr(e.Left, args)
+		if err != nil {
+			return "", err
+		}
+
+		right, err := b.buildExpr(e.Right, args)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("(%s AND %s)", left, right), nil
+	case *OrExpr:
+		left, err := b.buildExpr(e.Left, args)
+		if err != nil {
+			return "", err
+		}
+
+		right, err := b.buildExpr(e.Right, args)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("(%s OR %s)", left, right), nil
+	default:
+		return "", fmt.Errorf("unsupported expression %T: %w", expr, ErrInvalidQuery)
+	}
+}
+
+func (b *SQLBuilder) buildComparison(c *Comparison, args *[]any) (string, error) {
+	lhs := c.Field
+
+	if c.Func != "" {
+		rendered, err := b.renderFunc(c.Func, c.Field, nil, args)
+		if err != nil {
+			return "", err
+		}
+
+		lhs = rendered
+	}
+
+	val, err := convertLiteral(c.Value)
+	if err != nil {
+		return "", err
+	}
+
+	*args = append(*args, val)
+
+	return fmt.Sprintf("%s %s %s", lhs, c.Op, b.Dialect.Placeholder(len(*args))), nil
+}
+
+func (b *SQLBuilder) buildIn(e *InExpr, args *[]any) (string, error) {
+	placeholders := make([]string, len(e.Values))
+
+	for i, raw := range e.Values {
+		val, err := convertLiteral(raw)
+		if err != nil {
+			return "", err
+		}
+
+		*args = append(*args, val)
+		placeholders[i] = b.Dialect.Placeholder(len(*args))
+	}
+
+	return fmt.Sprintf("%s IN (%s)", e.Field, strings.Join(placeholders, ", ")), nil
+}
+
+// convertLiteral converts a raw literal produced by the lexer into a typed
+// value suitable for binding as a driver arg: quoted strings become a Go
+// string, or a time.Time when they parse as ISO-8601; bare numbers become
+// int64, falling back to float64.
+func convertLiteral(raw string) (any, error) {
+	if strings.HasPrefix(raw, "'") && strings.HasSuffix(raw, "'") {
+		s := strings.Trim(raw, "'")
+
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return t, nil
+		}
+
+		return s, nil
+	}
+
+	if i, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return i, nil
+	}
+
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid numeric literal %q: %w", raw, ErrInvalidQuery)
+	}
+
+	return f, nil
+}
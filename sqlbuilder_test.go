@@ -0,0 +1,87 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSQLBuilder_Build_BindsLiteralsAsArgsNotInline(t *testing.T) {
+	p, err := NewParser("Rate gt 100 and Genre eq 'Thriller'", testFields)
+	if err != nil {
+		t.Fatalf("NewParser: %v", err)
+	}
+
+	expr, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	f := &DataFilter{Filter: expr}
+
+	sql, args, err := NewSQLBuilder(PostgresDialect{}).Build(f)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	wantSQL := "WHERE (rate > $1 AND genre = $2)"
+	if sql != wantSQL {
+		t.Fatalf("sql = %q, want %q", sql, wantSQL)
+	}
+
+	wantArgs := []any{int64(100), "Thriller"}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Fatalf("args = %#v, want %#v", args, wantArgs)
+	}
+}
+
+func TestSQLBuilder_Build_OrderByAndPagination(t *testing.T) {
+	top, skip := 10, 5
+	nullsLast := false
+
+	f := &DataFilter{
+		OrderBy: []SortKey{
+			{Field: "title", Desc: true, NullsFirst: &nullsLast},
+			{Field: "rate"},
+		},
+		Top:  &top,
+		Skip: &skip,
+	}
+
+	sql, _, err := NewSQLBuilder(PostgresDialect{}).Build(f)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	want := "\nORDER BY title DESC NULLS LAST, rate ASC\nOFFSET 5\nLIMIT 10"
+	if sql != want {
+		t.Fatalf("sql = %q, want %q", sql, want)
+	}
+}
+
+func TestConvertLiteral(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want any
+	}{
+		{"42", int64(42)},
+		{"3.14", 3.14},
+		{"'hello'", "hello"},
+	}
+
+	for _, tt := range tests {
+		got, err := convertLiteral(tt.raw)
+		if err != nil {
+			t.Fatalf("convertLiteral(%q): %v", tt.raw, err)
+		}
+
+		if got != tt.want {
+			t.Errorf("convertLiteral(%q) = %#v, want %#v", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestConvertLiteral_InvalidNumeric(t *testing.T) {
+	if _, err := convertLiteral("not-a-number"); err == nil {
+		t.Fatal("expected error for invalid numeric literal")
+	}
+}